@@ -1,45 +1,146 @@
 package channelx
 
 import (
+	"context"
+	"fmt"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Represents the aggregator
-type Aggregator struct {
-	option         AggregatorOption
+// highWatermarkRatio is the eventQueue fill ratio above which a dynamic
+// aggregator spawns another worker.
+const highWatermarkRatio = 0.8
+
+// defaultMaxRestarts is the MaxRestarts applied when a caller doesn't set
+// one via a SetAggregatorOptionFuncOf. Baked into NewAggregatorOf's initial
+// option literal (like BatchSize/Workers/LingerTime) rather than as a
+// fallback in superviseWorker, so a caller who explicitly sets MaxRestarts
+// to 0 gets "never restart" to match MaxRetries' <=0-disables convention,
+// instead of silently getting this default back.
+const defaultMaxRestarts = 5
+
+// AggregatorOf is the generic, type-parameterized aggregator. It avoids the
+// per-item interface{} allocation on eventQueue and the type assertions
+// users would otherwise write in BatchProcessFuncOf. Aggregator is a thin
+// interface{}-instantiated wrapper over this type kept for compatibility.
+type AggregatorOf[T any] struct {
+	option         AggregatorOptionOf[T]
 	wg             *sync.WaitGroup
 	quit           chan struct{}
-	eventQueue     chan interface{}
-	batchProcessor BatchProcessFunc
+	quitOnce       sync.Once
+	eventQueue     chan T
+	batchProcessor BatchProcessFuncOf[T]
+	ctx            context.Context
+	cancel         context.CancelFunc
+	dynamic        bool
+	activeWorkers  int32
+	workerSeq      int32
 }
 
-// Represents the aggregator option
-type AggregatorOption struct {
+// AggregatorOptionOf is the generic, type-parameterized aggregator option.
+type AggregatorOptionOf[T any] struct {
 	BatchSize         int
 	Workers           int
 	ChannelBufferSize int
 	LingerTime        time.Duration
-	ErrorHandler      ErrorHandlerFunc
+	ErrorHandler      ErrorHandlerFuncOf[T]
 	Logger            Logger
+	// Context is the base context plumbed through to BatchProcessFuncOf and
+	// cancelled when StopCtx's deadline expires before the queue drains.
+	// Defaults to context.Background() when unset.
+	Context context.Context
+	// MinWorkers is the number of workers spawned at Start when MaxWorkers is
+	// set, and the floor auto-scaling will not shrink below. Defaults to 1.
+	MinWorkers int
+	// MaxWorkers enables auto-scaling: workers are spawned on demand as the
+	// queue fills up, instead of pre-spawning Workers goroutines at Start.
+	// Leave unset (0) to keep the static pool behavior driven by Workers.
+	MaxWorkers int
+	// WorkerIdleDuration is how long an auto-scaled worker waits for an item
+	// before exiting, once above MinWorkers. Defaults to 1 minute.
+	WorkerIdleDuration time.Duration
+	// MaxBatchBytes flushes the current batch as soon as its accumulated
+	// Sizer total reaches this many bytes, in addition to the BatchSize
+	// count limit. Leave unset (0) to ignore byte budget entirely.
+	MaxBatchBytes int
+	// Sizer measures the byte size of an item, used against MaxBatchBytes
+	// and passed to BatchReady as the running batch total. Required for
+	// MaxBatchBytes to have any effect; defaults to always returning 0.
+	Sizer func(item T) int
+	// BatchReady is an escape hatch evaluated after every item is appended,
+	// in addition to BatchSize and MaxBatchBytes: returning true flushes the
+	// batch immediately.
+	BatchReady func(batch []T, totalBytes int) bool
+	// MaxRetries is how many times a failing batch is resubmitted to
+	// batchProcessor before it is handed to DeadLetterHandler. Leave unset
+	// (0) to keep the previous behavior of going straight to ErrorHandler.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponentially growing delay between retries.
+	// Leave unset (0) for no cap.
+	MaxBackoff time.Duration
+	// BackoffJitter randomizes each backoff by a uniform factor in
+	// [1-BackoffJitter, 1+BackoffJitter]. Leave unset (0) to disable jitter.
+	BackoffJitter float64
+	// RetryPredicate decides whether an error is worth retrying. Leave nil
+	// to retry every error up to MaxRetries.
+	RetryPredicate func(err error) bool
+	// DeadLetterHandler receives a batch that exhausted MaxRetries (or
+	// whose error RetryPredicate rejected). Leave nil to fall back to
+	// ErrorHandler.
+	DeadLetterHandler func(items []T, lastErr error)
+	// MaxRestarts caps how many times a worker is restarted after its batching
+	// loop panics (batchProcessor panics are recovered by runBatchProcessor
+	// and do not crash the worker), paced by the
+	// same InitialBackoff/MaxBackoff/BackoffJitter used for batch retries.
+	// Once exceeded the worker is given up on permanently. Defaults to 5;
+	// like MaxRetries, set it to 0 explicitly to disable restarts entirely.
+	MaxRestarts int
+	// Observer, when set, receives lifecycle callbacks for metrics and
+	// tracing. See ObserverOf for the individual hooks.
+	Observer ObserverOf[T]
+}
+
+// ObserverOf receives lifecycle callbacks from AggregatorOf for metrics and
+// tracing. Implementations must be safe for concurrent use, since hooks fire
+// from worker goroutines as well as Enqueue/TryEnqueue callers.
+type ObserverOf[T any] interface {
+	// OnEnqueue fires after an item is accepted onto the event queue.
+	OnEnqueue(queueLen int)
+	// OnEnqueueDropped fires when TryEnqueue gives up on a full queue.
+	OnEnqueueDropped(item T)
+	// OnBatchStart fires right before a batch is handed to batchProcessor.
+	OnBatchStart(size int)
+	// OnBatchEnd fires after batchProcessor returns, with the elapsed time
+	// and its error, if any.
+	OnBatchEnd(size int, dur time.Duration, err error)
+	// OnLingerFlush fires when a batch is flushed because LingerTime
+	// elapsed rather than because it reached BatchSize/MaxBatchBytes.
+	OnLingerFlush(size int)
+	// OnWorkerPanic fires with the recovered value when a worker or
+	// batchProcessor panics.
+	OnWorkerPanic(r interface{})
 }
 
-// the func to batch process items
-type BatchProcessFunc func([]interface{}) error
+// BatchProcessFuncOf is the func to batch process a slice of typed items.
+type BatchProcessFuncOf[T any] func(ctx context.Context, items []T) error
 
-// the func to set option for aggregator
-type SetAggregatorOptionFunc func(option AggregatorOption) AggregatorOption
+// SetAggregatorOptionFuncOf is the func to set option for AggregatorOf.
+type SetAggregatorOptionFuncOf[T any] func(option AggregatorOptionOf[T]) AggregatorOptionOf[T]
 
-// the func to handle error
-type ErrorHandlerFunc func(err error, items []interface{}, batchProcessFunc BatchProcessFunc, aggregator *Aggregator)
+// ErrorHandlerFuncOf is the func to handle a batch error for AggregatorOf.
+type ErrorHandlerFuncOf[T any] func(err error, items []T, batchProcessFunc BatchProcessFuncOf[T], aggregator *AggregatorOf[T])
 
-// Creates a new aggregator
-func NewAggregator(batchProcessor BatchProcessFunc, optionFuncs ...SetAggregatorOptionFunc) *Aggregator {
-	option := AggregatorOption{
-		BatchSize:  8,
-		Workers:    runtime.NumCPU(),
-		LingerTime: 1 * time.Minute,
+// NewAggregatorOf creates a new typed aggregator.
+func NewAggregatorOf[T any](batchProcessor BatchProcessFuncOf[T], optionFuncs ...SetAggregatorOptionFuncOf[T]) *AggregatorOf[T] {
+	option := AggregatorOptionOf[T]{
+		BatchSize:   8,
+		Workers:     runtime.NumCPU(),
+		LingerTime:  1 * time.Minute,
+		MaxRestarts: defaultMaxRestarts,
 	}
 
 	for _, optionFunc := range optionFuncs {
@@ -50,19 +151,151 @@ func NewAggregator(batchProcessor BatchProcessFunc, optionFuncs ...SetAggregator
 		option.ChannelBufferSize = option.Workers
 	}
 
-	return &Aggregator{
-		eventQueue:     make(chan interface{}, option.ChannelBufferSize),
+	baseCtx := option.Context
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(baseCtx)
+
+	dynamic := option.MaxWorkers > 0
+	if dynamic {
+		if option.MinWorkers <= 0 {
+			option.MinWorkers = 1
+		}
+		if option.MinWorkers > option.MaxWorkers {
+			option.MinWorkers = option.MaxWorkers
+		}
+		if option.WorkerIdleDuration <= 0 {
+			option.WorkerIdleDuration = 1 * time.Minute
+		}
+	}
+
+	return &AggregatorOf[T]{
+		eventQueue:     make(chan T, option.ChannelBufferSize),
 		option:         option,
 		quit:           make(chan struct{}),
 		wg:             new(sync.WaitGroup),
 		batchProcessor: batchProcessor,
+		ctx:            ctx,
+		cancel:         cancel,
+		dynamic:        dynamic,
+	}
+}
+
+// ActiveWorkers returns the number of worker goroutines currently running.
+func (agt *AggregatorOf[T]) ActiveWorkers() int {
+	return int(atomic.LoadInt32(&agt.activeWorkers))
+}
+
+// QueueLen returns the number of items currently buffered in the event queue.
+func (agt *AggregatorOf[T]) QueueLen() int {
+	return len(agt.eventQueue)
+}
+
+// spawnWorker starts a new worker goroutine if the active worker count is
+// below MaxWorkers, reporting whether a worker was started.
+func (agt *AggregatorOf[T]) spawnWorker() bool {
+	for {
+		cur := atomic.LoadInt32(&agt.activeWorkers)
+		if int(cur) >= agt.option.MaxWorkers {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&agt.activeWorkers, cur, cur+1) {
+			index := int(atomic.AddInt32(&agt.workerSeq, 1))
+			agt.launchWorker(index)
+			return true
+		}
+	}
+}
+
+// launchWorker accounts for a new worker slot and starts it under
+// supervision. The matching wg.Done/activeWorkers decrement happens once,
+// when the slot is permanently retired (clean shutdown, or panic restarts
+// exhausted) rather than on every restart.
+func (agt *AggregatorOf[T]) launchWorker(index int) {
+	agt.wg.Add(1)
+	go agt.superviseWorker(index, 0)
+}
+
+// tryClaimIdleExit atomically claims the right for an idle worker to exit,
+// decrementing activeWorkers only if doing so would not drop the pool below
+// MinWorkers. Mirrors spawnWorker's CAS loop so that several workers going
+// idle at the same instant can't all observe room to exit and overshoot
+// MinWorkers before any of them actually decrements the counter.
+func (agt *AggregatorOf[T]) tryClaimIdleExit() bool {
+	for {
+		cur := atomic.LoadInt32(&agt.activeWorkers)
+		if int(cur) <= agt.option.MinWorkers {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&agt.activeWorkers, cur, cur-1) {
+			return true
+		}
+	}
+}
+
+// superviseWorker runs a worker and, if it panics, restarts it with backoff
+// up to MaxRestarts times before retiring the slot for good.
+func (agt *AggregatorOf[T]) superviseWorker(index int, restarts int) {
+	crashed, failedBatch, crashErr, idleExit := agt.work(index)
+	if !crashed {
+		agt.wg.Done()
+		if !idleExit {
+			atomic.AddInt32(&agt.activeWorkers, -1)
+		}
+		return
+	}
+
+	if len(failedBatch) != 0 {
+		agt.handleFailure(failedBatch, crashErr)
+	}
+
+	maxRestarts := agt.option.MaxRestarts
+	if restarts >= maxRestarts {
+		if agt.option.Logger != nil {
+			agt.option.Logger.Errorc("Aggregator", crashErr, "worker %d exceeded %d restarts, giving up", index, maxRestarts)
+		}
+		agt.wg.Done()
+		atomic.AddInt32(&agt.activeWorkers, -1)
+		return
+	}
+
+	timer := time.NewTimer(agt.nextBackoff(restarts))
+	select {
+	case <-timer.C:
+	case <-agt.quit:
+		timer.Stop()
+		agt.wg.Done()
+		atomic.AddInt32(&agt.activeWorkers, -1)
+		return
+	}
+
+	agt.superviseWorker(index, restarts+1)
+}
+
+// maybeScaleUp starts another worker when the event queue is above the high
+// watermark and the aggregator is running in dynamic mode.
+func (agt *AggregatorOf[T]) maybeScaleUp() {
+	if !agt.dynamic {
+		return
+	}
+
+	capacity := cap(agt.eventQueue)
+	if capacity == 0 {
+		return
+	}
+
+	if float64(len(agt.eventQueue)) > float64(capacity)*highWatermarkRatio {
+		agt.spawnWorker()
 	}
 }
 
 // Try enqueue an item, and it is non-blocked
-func (agt *Aggregator) TryEnqueue(item interface{}) bool {
+func (agt *AggregatorOf[T]) TryEnqueue(item T) bool {
 	select {
 	case agt.eventQueue <- item:
+		agt.maybeScaleUp()
+		agt.reportQueueLen()
 		return true
 	default:
 		if agt.option.Logger != nil {
@@ -73,124 +306,315 @@ func (agt *Aggregator) TryEnqueue(item interface{}) bool {
 
 		select {
 		case agt.eventQueue <- item:
+			agt.maybeScaleUp()
+			agt.reportQueueLen()
 			return true
 		default:
 			if agt.option.Logger != nil {
 				agt.option.Logger.Warnc("Aggregator", nil, "Event queue is still full and %+v is skipped.", item)
 			}
+			if agt.option.Observer != nil {
+				agt.option.Observer.OnEnqueueDropped(item)
+			}
 			return false
 		}
 	}
 }
 
 // Enqueue an item, will be blocked if the queue is full
-func (agt *Aggregator) Enqueue(item interface{}) {
+func (agt *AggregatorOf[T]) Enqueue(item T) {
 	agt.eventQueue <- item
+	agt.maybeScaleUp()
+	agt.reportQueueLen()
+}
+
+// EnqueueCtx enqueues an item, blocking if the queue is full until either the
+// item is accepted or ctx is done, in which case ctx.Err() is returned.
+func (agt *AggregatorOf[T]) EnqueueCtx(ctx context.Context, item T) error {
+	select {
+	case agt.eventQueue <- item:
+		agt.maybeScaleUp()
+		agt.reportQueueLen()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reportQueueLen reports the current queue length to Observer, if set.
+func (agt *AggregatorOf[T]) reportQueueLen() {
+	if agt.option.Observer != nil {
+		agt.option.Observer.OnEnqueue(len(agt.eventQueue))
+	}
 }
 
 // Start the aggregator
-func (agt *Aggregator) Start() {
+func (agt *AggregatorOf[T]) Start() {
+	if agt.dynamic {
+		for i := 0; i < agt.option.MinWorkers; i++ {
+			agt.spawnWorker()
+		}
+		return
+	}
+
 	for i := 0; i < agt.option.Workers; i++ {
 		index := i
-		go agt.work(index)
+		atomic.AddInt32(&agt.activeWorkers, 1)
+		agt.launchWorker(index)
 	}
 }
 
+// closeQuit closes agt.quit exactly once, since StopCtx can race the
+// background drain goroutine against its own ctx.Done() handler to do so.
+func (agt *AggregatorOf[T]) closeQuit() {
+	agt.quitOnce.Do(func() { close(agt.quit) })
+}
+
 // Stop the aggregator
-func (agt *Aggregator) Stop() {
-	close(agt.quit)
+func (agt *AggregatorOf[T]) Stop() {
+	agt.closeQuit()
 	agt.wg.Wait()
+	agt.cancel()
 }
 
 // Stop the aggregator safely, the difference with Stop is it guarantees no item is missed during stop
-func (agt *Aggregator) SafeStop() {
+func (agt *AggregatorOf[T]) SafeStop() {
 	if len(agt.eventQueue) == 0 {
-		close(agt.quit)
+		agt.closeQuit()
 	} else {
 		ticker := time.NewTicker(50 * time.Millisecond)
 		for range ticker.C {
 			if len(agt.eventQueue) == 0 {
-				close(agt.quit)
+				agt.closeQuit()
 				break
 			}
 		}
 		ticker.Stop()
 	}
 	agt.wg.Wait()
+	agt.cancel()
 }
 
-func (agt *Aggregator) work(index int) {
+// StopCtx behaves like SafeStop, draining the queue before shutting workers
+// down, but returns as soon as ctx is done instead of waiting for the drain
+// to finish. The aggregator's base context is cancelled first so in-flight
+// batches passed to BatchProcessFuncOf can observe it and abort early, but
+// since a misbehaving BatchProcessFuncOf may ignore ctx entirely, StopCtx
+// does not wait on it: it force-closes agt.quit and returns ctx.Err()
+// immediately, leaving the drain goroutine to finish in the background.
+func (agt *AggregatorOf[T]) StopCtx(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+
+		if len(agt.eventQueue) == 0 {
+			agt.closeQuit()
+		} else {
+			ticker := time.NewTicker(50 * time.Millisecond)
+		poll:
+			for {
+				select {
+				case <-ticker.C:
+					if len(agt.eventQueue) == 0 {
+						agt.closeQuit()
+						break poll
+					}
+				case <-agt.quit:
+					// Force-closed by the ctx.Done() branch below: once quit
+					// is closed, workers stop consuming the queue, so it may
+					// never empty on its own. Stop polling for that.
+					break poll
+				}
+			}
+			ticker.Stop()
+		}
+		agt.wg.Wait()
+	}()
+
+	select {
+	case <-drained:
+		agt.cancel()
+		return nil
+	case <-ctx.Done():
+		agt.cancel()
+		agt.closeQuit()
+		return ctx.Err()
+	}
+}
+
+// work runs the batching loop for a single worker until agt.quit is closed,
+// reporting crashed=true and the in-flight batch if it recovers from a
+// panic. Lifecycle accounting (wg, activeWorkers) is the caller's
+// responsibility via superviseWorker, so restarts don't double count it,
+// except for idleExit: a voluntary idle-timeout exit claims its
+// activeWorkers decrement itself (see tryClaimIdleExit) so concurrently
+// idling workers can't overshoot MinWorkers, and reports that back via
+// idleExit so superviseWorker doesn't decrement a second time.
+func (agt *AggregatorOf[T]) work(index int) (crashed bool, failedBatch []T, crashErr error, idleExit bool) {
+	batch := make([]T, 0, agt.option.BatchSize)
 	defer func() {
 		if r := recover(); r != nil {
+			crashed = true
+			failedBatch = batch
+			crashErr = fmt.Errorf("%w: %v", ErrWorkerCrashed, r)
 			if agt.option.Logger != nil {
-				agt.option.Logger.Errorc("Aggregator", nil, "recover worker as bad thing happens %+v", r)
+				agt.option.Logger.Errorc("Aggregator", crashErr, "recover worker as bad thing happens %+v", r)
+			}
+			if agt.option.Observer != nil {
+				agt.option.Observer.OnWorkerPanic(r)
 			}
-
-			agt.work(index)
 		}
 	}()
 
-	agt.wg.Add(1)
-	defer agt.wg.Done()
-
-	batch := make([]interface{}, 0, agt.option.BatchSize)
+	batchBytes := 0
 	lingerTimer := time.NewTimer(0)
 	if !lingerTimer.Stop() {
 		<-lingerTimer.C
 	}
 	defer lingerTimer.Stop()
 
+	var idleTimer *time.Timer
+	var idleC <-chan time.Time
+	if agt.dynamic {
+		idleTimer = time.NewTimer(agt.option.WorkerIdleDuration)
+		defer idleTimer.Stop()
+		idleC = idleTimer.C
+	}
+
 loop:
 	for {
 		select {
 		case req := <-agt.eventQueue:
+			if agt.dynamic {
+				if !idleTimer.Stop() {
+					<-idleTimer.C
+				}
+				idleTimer.Reset(agt.option.WorkerIdleDuration)
+			}
+
 			batch = append(batch, req)
+			if agt.option.Sizer != nil {
+				batchBytes += agt.option.Sizer(req)
+			}
 
 			batchSize := len(batch)
-			if batchSize < agt.option.BatchSize {
-				if batchSize == 1 {
-					lingerTimer.Reset(agt.option.LingerTime)
-				}
+			if batchSize == 1 {
+				lingerTimer.Reset(agt.option.LingerTime)
+			}
+
+			ready := batchSize >= agt.option.BatchSize
+			if !ready && agt.option.MaxBatchBytes > 0 && batchBytes >= agt.option.MaxBatchBytes {
+				ready = true
+			}
+			if !ready && agt.option.BatchReady != nil && agt.option.BatchReady(batch, batchBytes) {
+				ready = true
+			}
+			if !ready {
 				break
 			}
 
-			agt.batchProcess(batch)
+			agt.batchProcess(agt.ctx, batch)
 
 			if !lingerTimer.Stop() {
 				<-lingerTimer.C
 			}
-			batch = make([]interface{}, 0, agt.option.BatchSize)
+			batch = make([]T, 0, agt.option.BatchSize)
+			batchBytes = 0
 		case <-lingerTimer.C:
 			if len(batch) == 0 {
 				break
 			}
 
-			agt.batchProcess(batch)
-			batch = make([]interface{}, 0, agt.option.BatchSize)
+			if agt.option.Observer != nil {
+				agt.option.Observer.OnLingerFlush(len(batch))
+			}
+
+			agt.batchProcess(agt.ctx, batch)
+			batch = make([]T, 0, agt.option.BatchSize)
+			batchBytes = 0
+		case <-idleC:
+			if len(batch) != 0 {
+				agt.batchProcess(agt.ctx, batch)
+				batch = make([]T, 0, agt.option.BatchSize)
+				batchBytes = 0
+			}
+
+			if agt.tryClaimIdleExit() {
+				idleExit = true
+				break loop
+			}
+
+			idleTimer.Reset(agt.option.WorkerIdleDuration)
 		case <-agt.quit:
 			if len(batch) != 0 {
-				agt.batchProcess(batch)
+				agt.batchProcess(agt.ctx, batch)
 			}
 
 			break loop
 		}
 	}
+
+	return false, nil, nil, idleExit
 }
 
-func (agt *Aggregator) batchProcess(items []interface{}) {
+func (agt *AggregatorOf[T]) batchProcess(ctx context.Context, items []T) {
 	agt.wg.Add(1)
 	defer agt.wg.Done()
-	if err := agt.batchProcessor(items); err != nil {
+
+	if agt.option.Observer != nil {
+		agt.option.Observer.OnBatchStart(len(items))
+	}
+	start := time.Now()
+	err := agt.runBatchProcessor(ctx, items)
+	if agt.option.Observer != nil {
+		agt.option.Observer.OnBatchEnd(len(items), time.Since(start), err)
+	}
+
+	if err != nil {
 		if agt.option.Logger != nil {
 			agt.option.Logger.Errorc("Aggregator", err, "error happens")
 		}
 
-		if agt.option.ErrorHandler != nil {
-			go agt.option.ErrorHandler(err, items, agt.batchProcessor, agt)
-		} else if agt.option.Logger != nil {
-			agt.option.Logger.Errorc("Aggregator", err, "error happens in batchProcess and is skipped")
+		if agt.shouldRetry(err) {
+			agt.wg.Add(1)
+			go agt.retryLoop(ctx, items, err)
+			return
 		}
+
+		if agt.option.MaxRetries > 0 {
+			// RetryPredicate rejected the error.
+			agt.deadLetter(items, err)
+			return
+		}
+
+		agt.handleFailure(items, err)
 	} else if agt.option.Logger != nil {
 		agt.option.Logger.Infoc("Aggregator", "%d items have been sent.", len(items))
 	}
 }
+
+// runBatchProcessor invokes batchProcessor, recovering a panic into an
+// ErrWorkerCrashed-wrapped error so a misbehaving processor flows through
+// the normal retry/dead-letter path instead of crashing the worker.
+func (agt *AggregatorOf[T]) runBatchProcessor(ctx context.Context, items []T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrWorkerCrashed, r)
+			if agt.option.Observer != nil {
+				agt.option.Observer.OnWorkerPanic(r)
+			}
+		}
+	}()
+
+	return agt.batchProcessor(ctx, items)
+}
+
+// handleFailure routes a batch that will not be retried to ErrorHandler, or
+// logs it as skipped when no handler is configured.
+func (agt *AggregatorOf[T]) handleFailure(items []T, err error) {
+	if agt.option.ErrorHandler != nil {
+		go agt.option.ErrorHandler(err, items, agt.batchProcessor, agt)
+	} else if agt.option.Logger != nil {
+		agt.option.Logger.Errorc("Aggregator", err, "error happens in batchProcess and is skipped")
+	}
+}
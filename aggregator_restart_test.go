@@ -0,0 +1,94 @@
+package channelx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSuperviseWorkerRestartsAfterPanic drives panics through Sizer (rather
+// than batchProcessor, which runBatchProcessor already guards on its own) to
+// exercise superviseWorker's own restart/backoff path, and checks the worker
+// pool keeps making progress across restarts instead of dying for good.
+func TestSuperviseWorkerRestartsAfterPanic(t *testing.T) {
+	var sizerCalls int32
+	var processed int32
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		atomic.AddInt32(&processed, 1)
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 1
+		o.ChannelBufferSize = 4
+		o.MaxRestarts = 3
+		o.InitialBackoff = time.Millisecond
+		o.Sizer = func(item int) int {
+			if atomic.AddInt32(&sizerCalls, 1) <= 2 {
+				panic("boom")
+			}
+			return 0
+		}
+		return o
+	})
+	agg.Start()
+
+	agg.Enqueue(1)
+	agg.Enqueue(2)
+	agg.Enqueue(3)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&processed) < 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	agg.Stop()
+
+	if got := atomic.LoadInt32(&sizerCalls); got < 3 {
+		t.Fatalf("expected the worker to restart twice and keep calling Sizer, got %d calls", got)
+	}
+	if got := atomic.LoadInt32(&processed); got < 1 {
+		t.Fatalf("expected the pool to keep making progress after restarts, got %d processed batches", got)
+	}
+}
+
+// TestMaxRestartsZeroDisablesRestart checks that MaxRestarts: 0 means "never
+// restart", matching MaxRetries' <=0-disables convention, rather than
+// silently falling back to the package default.
+func TestMaxRestartsZeroDisablesRestart(t *testing.T) {
+	var sizerCalls int32
+	gaveUp := make(chan struct{}, 1)
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 1
+		o.ChannelBufferSize = 4
+		o.MaxRestarts = 0
+		o.Sizer = func(item int) int {
+			atomic.AddInt32(&sizerCalls, 1)
+			panic("boom")
+		}
+		o.ErrorHandler = func(err error, items []int, fn BatchProcessFuncOf[int], a *AggregatorOf[int]) {
+			select {
+			case gaveUp <- struct{}{}:
+			default:
+			}
+		}
+		return o
+	})
+	agg.Start()
+	agg.Enqueue(1)
+
+	select {
+	case <-gaveUp:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the panicking worker to give up via ErrorHandler without restarting")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&sizerCalls); got != 1 {
+		t.Fatalf("expected exactly 1 Sizer call with MaxRestarts=0 (no restart), got %d", got)
+	}
+}
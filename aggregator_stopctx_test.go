@@ -0,0 +1,44 @@
+package channelx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestStopCtxRespectsDeadline reproduces a processor that never checks ctx
+// and a non-empty queue: StopCtx must still return once its own ctx expires,
+// instead of blocking on the drain/wg.Wait() indefinitely.
+func TestStopCtxRespectsDeadline(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		<-block
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 1
+		o.ChannelBufferSize = 4
+		return o
+	})
+	agg.Start()
+
+	agg.Enqueue(1)
+	time.Sleep(20 * time.Millisecond) // let the worker pick item 1 up and start blocking
+	agg.Enqueue(2)                    // queue stays non-empty for the whole StopCtx call
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := agg.StopCtx(ctx)
+	elapsed := time.Since(start)
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Fatalf("StopCtx took %v, expected it to return shortly after its 100ms deadline", elapsed)
+	}
+}
@@ -0,0 +1,27 @@
+package channelx
+
+// Aggregator is the interface{}-based aggregator kept for backwards
+// compatibility. Prefer AggregatorOf[T] for new code: it removes the
+// per-item interface{} allocation on the event queue and the type
+// assertions callers would otherwise write in BatchProcessFunc.
+type Aggregator = AggregatorOf[interface{}]
+
+// AggregatorOption is the interface{}-based predecessor of AggregatorOptionOf.
+type AggregatorOption = AggregatorOptionOf[interface{}]
+
+// the func to batch process items
+type BatchProcessFunc = BatchProcessFuncOf[interface{}]
+
+// the func to set option for aggregator
+type SetAggregatorOptionFunc = SetAggregatorOptionFuncOf[interface{}]
+
+// the func to handle error
+type ErrorHandlerFunc = ErrorHandlerFuncOf[interface{}]
+
+// Observer is the interface{}-based predecessor of ObserverOf.
+type Observer = ObserverOf[interface{}]
+
+// Creates a new aggregator
+func NewAggregator(batchProcessor BatchProcessFunc, optionFuncs ...SetAggregatorOptionFunc) *Aggregator {
+	return NewAggregatorOf[interface{}](batchProcessor, optionFuncs...)
+}
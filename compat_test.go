@@ -0,0 +1,96 @@
+package channelx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestNewAggregatorProcessesInterfaceItems checks that the interface{}-based
+// Aggregator wrapper behaves like AggregatorOf[interface{}] end-to-end:
+// items enqueued as interface{} values reach batchProcessor untouched.
+func TestNewAggregatorProcessesInterfaceItems(t *testing.T) {
+	received := make(chan []interface{}, 1)
+
+	agg := NewAggregator(func(ctx context.Context, items []interface{}) error {
+		received <- items
+		return nil
+	}, func(o AggregatorOption) AggregatorOption {
+		o.Workers = 1
+		o.BatchSize = 2
+		o.ChannelBufferSize = 4
+		return o
+	})
+	agg.Start()
+
+	agg.Enqueue("a")
+	agg.Enqueue(42)
+
+	select {
+	case items := <-received:
+		if len(items) != 2 || items[0] != "a" || items[1] != 42 {
+			t.Fatalf("expected batch [a 42], got %v", items)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected batchProcessor to receive the enqueued items")
+	}
+	agg.Stop()
+}
+
+// TestNewAggregatorIsAnAggregatorOf checks that NewAggregator really is a
+// thin instantiation of AggregatorOf[interface{}], not a separate type, so
+// the two share behavior (here: TryEnqueue on a full, unconsumed queue).
+func TestNewAggregatorIsAnAggregatorOf(t *testing.T) {
+	var agg *AggregatorOf[interface{}] = NewAggregator(func(ctx context.Context, items []interface{}) error {
+		return nil
+	}, func(o AggregatorOption) AggregatorOption {
+		o.Workers = 0
+		o.ChannelBufferSize = 1
+		return o
+	})
+
+	if !agg.TryEnqueue("first") {
+		t.Fatal("expected the first TryEnqueue into an empty, unconsumed queue to succeed")
+	}
+	if agg.TryEnqueue("second") {
+		t.Fatal("expected TryEnqueue to report false once the queue is full and nothing is consuming it")
+	}
+}
+
+// TestGenericAggregatorOfWithStructType checks AggregatorOf[T] with a
+// non-trivial T, confirming no interface{} boxing/assertions are needed.
+func TestGenericAggregatorOfWithStructType(t *testing.T) {
+	type event struct {
+		id   int
+		name string
+	}
+
+	var total int32
+	agg := NewAggregatorOf[event](func(ctx context.Context, items []event) error {
+		for _, e := range items {
+			atomic.AddInt32(&total, int32(e.id))
+		}
+		return nil
+	}, func(o AggregatorOptionOf[event]) AggregatorOptionOf[event] {
+		o.Workers = 1
+		o.BatchSize = 3
+		o.ChannelBufferSize = 4
+		return o
+	})
+	agg.Start()
+
+	agg.Enqueue(event{id: 1, name: "a"})
+	agg.Enqueue(event{id: 2, name: "b"})
+	agg.Enqueue(event{id: 3, name: "c"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&total) != 6 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	agg.Stop()
+
+	if got := atomic.LoadInt32(&total); got != 6 {
+		t.Fatalf("expected batchProcessor to see all 3 events (sum of ids = 6), got %d", got)
+	}
+}
@@ -0,0 +1,125 @@
+package channelx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDynamicScaleUpOnHighWatermark checks that a dynamic aggregator spawns
+// workers past MinWorkers once the event queue crosses the high watermark.
+func TestDynamicScaleUpOnHighWatermark(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		<-block
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.BatchSize = 1
+		o.Workers = 1 // ChannelBufferSize is raised to Workers when smaller
+		o.MinWorkers = 1
+		o.MaxWorkers = 4
+		o.ChannelBufferSize = 5
+		return o
+	})
+	agg.Start()
+
+	if got := agg.ActiveWorkers(); got != 1 {
+		t.Fatalf("expected 1 worker at start (MinWorkers), got %d", got)
+	}
+
+	// The sole worker blocks on the first item, so the other 5 pile up past
+	// the 5*0.8=4 high watermark.
+	for i := 0; i < 6; i++ {
+		agg.Enqueue(i)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for agg.ActiveWorkers() <= 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := agg.ActiveWorkers(); got <= 1 {
+		t.Fatalf("expected the pool to scale up past MinWorkers once the queue crossed the high watermark, got %d active workers", got)
+	}
+}
+
+// TestDynamicScaleDownToMinWorkers checks that workers spawned for a burst
+// exit once idle, settling back down to exactly MinWorkers.
+func TestDynamicScaleDownToMinWorkers(t *testing.T) {
+	var processed int32
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		atomic.AddInt32(&processed, 1)
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.BatchSize = 1
+		o.MinWorkers = 1
+		o.MaxWorkers = 4
+		o.ChannelBufferSize = 8
+		o.WorkerIdleDuration = 20 * time.Millisecond
+		return o
+	})
+	agg.Start()
+
+	for i := 0; i < 8; i++ {
+		agg.Enqueue(i)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&processed) < 8 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	deadline = time.Now().Add(2 * time.Second)
+	for agg.ActiveWorkers() > 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := agg.ActiveWorkers(); got != 1 {
+		t.Fatalf("expected the pool to settle back at MinWorkers=1, got %d active workers", got)
+	}
+	agg.Stop()
+}
+
+// TestIdleExitNeverUndercutsMinWorkers drives several workers idle at once
+// and checks the pool never dips below MinWorkers while they race to exit,
+// guarding the CAS-based claim in tryClaimIdleExit.
+func TestIdleExitNeverUndercutsMinWorkers(t *testing.T) {
+	var processed int32
+	const minWorkers = 2
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		atomic.AddInt32(&processed, 1)
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.BatchSize = 1
+		o.MinWorkers = minWorkers
+		o.MaxWorkers = 6
+		o.ChannelBufferSize = 8
+		o.WorkerIdleDuration = 15 * time.Millisecond
+		return o
+	})
+	agg.Start()
+
+	for i := 0; i < 6; i++ {
+		agg.Enqueue(i)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&processed) < 6 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	settleDeadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(settleDeadline) {
+		if got := agg.ActiveWorkers(); got < minWorkers {
+			t.Fatalf("active workers dropped to %d during idle-exit settle-down, below MinWorkers=%d", got, minWorkers)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	agg.Stop()
+}
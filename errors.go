@@ -0,0 +1,8 @@
+package channelx
+
+import "errors"
+
+// ErrWorkerCrashed wraps the recovered panic value when a worker goroutine
+// or batchProcessor panics, so callers can distinguish crash-induced
+// failures from ordinary batchProcessor errors via errors.Is.
+var ErrWorkerCrashed = errors.New("channelx: worker crashed")
@@ -0,0 +1,13 @@
+package channelx
+
+// Logger is the logging hook AggregatorOptionOf accepts, matching the
+// tag/err/format-args call sites used throughout this package. Leave unset
+// to disable logging entirely.
+type Logger interface {
+	// Infoc logs an informational message tagged with tag.
+	Infoc(tag string, format string, args ...interface{})
+	// Warnc logs a warning tagged with tag, with an optional associated err.
+	Warnc(tag string, err error, format string, args ...interface{})
+	// Errorc logs an error tagged with tag.
+	Errorc(tag string, err error, format string, args ...interface{})
+}
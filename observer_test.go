@@ -0,0 +1,157 @@
+package channelx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeObserver records every ObserverOf hook call for assertions, guarded by
+// a mutex since hooks fire from worker goroutines as well as callers of
+// Enqueue/TryEnqueue.
+type fakeObserver struct {
+	mu            sync.Mutex
+	enqueued      []int
+	dropped       []int
+	batchStarts   []int
+	batchEnds     int32
+	lingerFlushes []int
+	panics        []interface{}
+}
+
+func (o *fakeObserver) OnEnqueue(queueLen int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.enqueued = append(o.enqueued, queueLen)
+}
+
+func (o *fakeObserver) OnEnqueueDropped(item int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.dropped = append(o.dropped, item)
+}
+
+func (o *fakeObserver) OnBatchStart(size int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.batchStarts = append(o.batchStarts, size)
+}
+
+func (o *fakeObserver) OnBatchEnd(size int, dur time.Duration, err error) {
+	atomic.AddInt32(&o.batchEnds, 1)
+}
+
+func (o *fakeObserver) OnLingerFlush(size int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.lingerFlushes = append(o.lingerFlushes, size)
+}
+
+func (o *fakeObserver) OnWorkerPanic(r interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.panics = append(o.panics, r)
+}
+
+func (o *fakeObserver) snapshot() (enqueued, dropped, batchStarts []int, batchEnds int32, lingerFlushes []int, panics []interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]int(nil), o.enqueued...), append([]int(nil), o.dropped...),
+		append([]int(nil), o.batchStarts...), atomic.LoadInt32(&o.batchEnds),
+		append([]int(nil), o.lingerFlushes...), append([]interface{}(nil), o.panics...)
+}
+
+// TestObserverSeesBatchLifecycle checks OnEnqueue/OnBatchStart/OnBatchEnd
+// fire for a normal batch.
+func TestObserverSeesBatchLifecycle(t *testing.T) {
+	obs := &fakeObserver{}
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 2
+		o.ChannelBufferSize = 4
+		o.Observer = obs
+		return o
+	})
+	agg.Start()
+
+	agg.Enqueue(1)
+	agg.Enqueue(2)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&obs.batchEnds) < 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	agg.Stop()
+
+	enqueued, _, batchStarts, batchEnds, _, _ := obs.snapshot()
+	if len(enqueued) != 2 {
+		t.Fatalf("expected 2 OnEnqueue calls, got %d", len(enqueued))
+	}
+	if len(batchStarts) != 1 || batchStarts[0] != 2 {
+		t.Fatalf("expected 1 OnBatchStart(2), got %v", batchStarts)
+	}
+	if batchEnds != 1 {
+		t.Fatalf("expected 1 OnBatchEnd, got %d", batchEnds)
+	}
+}
+
+// TestObserverSeesDroppedItem checks OnEnqueueDropped fires when TryEnqueue
+// gives up on a full, unconsumed queue.
+func TestObserverSeesDroppedItem(t *testing.T) {
+	obs := &fakeObserver{}
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 0
+		o.ChannelBufferSize = 1
+		o.Observer = obs
+		return o
+	})
+
+	if !agg.TryEnqueue(1) {
+		t.Fatal("expected the first TryEnqueue to succeed")
+	}
+	if agg.TryEnqueue(2) {
+		t.Fatal("expected the second TryEnqueue to be dropped")
+	}
+
+	_, dropped, _, _, _, _ := obs.snapshot()
+	if len(dropped) != 1 || dropped[0] != 2 {
+		t.Fatalf("expected OnEnqueueDropped(2), got %v", dropped)
+	}
+}
+
+// TestObserverSeesWorkerPanic checks OnWorkerPanic fires with the recovered
+// value when the worker loop itself panics (via Sizer, which work() does
+// not guard the way it guards batchProcessor).
+func TestObserverSeesWorkerPanic(t *testing.T) {
+	obs := &fakeObserver{}
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 1
+		o.ChannelBufferSize = 4
+		o.MaxRestarts = 1
+		o.InitialBackoff = time.Millisecond
+		o.Observer = obs
+		o.Sizer = func(item int) int { panic(errors.New("sizer boom")) }
+		return o
+	})
+	agg.Start()
+	agg.Enqueue(1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, _, _, _, _, panics := obs.snapshot(); len(panics) > 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("expected OnWorkerPanic to fire after the Sizer panic")
+}
@@ -0,0 +1,90 @@
+// Package prometheus provides a ready-made channelx.Observer that exposes
+// aggregator activity as Prometheus metrics.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/zxlwdgithub/channelx"
+)
+
+// Observer records channelx.Aggregator lifecycle events as Prometheus
+// batch_size/batch_duration_seconds histograms, a queue_length gauge, and
+// drops_total/worker_panics_total counters.
+type Observer struct {
+	batchSize     prometheus.Histogram
+	batchDuration prometheus.Histogram
+	queueLength   prometheus.Gauge
+	drops         prometheus.Counter
+	workerPanics  prometheus.Counter
+}
+
+var _ channelx.Observer = (*Observer)(nil)
+
+// NewObserver builds an Observer and registers its metrics with reg, using
+// namespace as the Prometheus metric namespace.
+func NewObserver(reg prometheus.Registerer, namespace string) *Observer {
+	o := &Observer{
+		batchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_size",
+			Help:      "Number of items in a batch handed to batchProcessor.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}),
+		batchDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_duration_seconds",
+			Help:      "Time spent in batchProcessor per batch.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		queueLength: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_length",
+			Help:      "Number of items currently buffered in the event queue.",
+		}),
+		drops: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "drops_total",
+			Help:      "Number of items dropped because the event queue was full.",
+		}),
+		workerPanics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "worker_panics_total",
+			Help:      "Number of worker goroutine panics recovered.",
+		}),
+	}
+
+	reg.MustRegister(o.batchSize, o.batchDuration, o.queueLength, o.drops, o.workerPanics)
+
+	return o
+}
+
+// OnEnqueue implements channelx.Observer.
+func (o *Observer) OnEnqueue(queueLen int) {
+	o.queueLength.Set(float64(queueLen))
+}
+
+// OnEnqueueDropped implements channelx.Observer.
+func (o *Observer) OnEnqueueDropped(item interface{}) {
+	o.drops.Inc()
+}
+
+// OnBatchStart implements channelx.Observer.
+func (o *Observer) OnBatchStart(size int) {
+	o.batchSize.Observe(float64(size))
+}
+
+// OnBatchEnd implements channelx.Observer.
+func (o *Observer) OnBatchEnd(size int, dur time.Duration, err error) {
+	o.batchDuration.Observe(dur.Seconds())
+}
+
+// OnLingerFlush implements channelx.Observer.
+func (o *Observer) OnLingerFlush(size int) {}
+
+// OnWorkerPanic implements channelx.Observer.
+func (o *Observer) OnWorkerPanic(r interface{}) {
+	o.workerPanics.Inc()
+}
@@ -0,0 +1,85 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := g.Write(&m); err != nil {
+		t.Fatalf("failed to read gauge: %v", err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+// TestObserverRecordsCounters checks each channelx.Observer hook updates the
+// Prometheus metric it's documented to.
+func TestObserverRecordsCounters(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	o := NewObserver(reg, "channelx_test")
+
+	o.OnEnqueue(3)
+	if got := gaugeValue(t, o.queueLength); got != 3 {
+		t.Fatalf("expected queue_length=3 after OnEnqueue(3), got %v", got)
+	}
+
+	o.OnEnqueue(1)
+	if got := gaugeValue(t, o.queueLength); got != 1 {
+		t.Fatalf("expected queue_length=1 after OnEnqueue(1), got %v", got)
+	}
+
+	o.OnEnqueueDropped("x")
+	o.OnEnqueueDropped("y")
+	if got := counterValue(t, o.drops); got != 2 {
+		t.Fatalf("expected drops_total=2, got %v", got)
+	}
+
+	o.OnWorkerPanic("boom")
+	if got := counterValue(t, o.workerPanics); got != 1 {
+		t.Fatalf("expected worker_panics_total=1, got %v", got)
+	}
+
+	o.OnBatchStart(5)
+	o.OnBatchEnd(5, 10*time.Millisecond, nil)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	var sawBatchSize, sawBatchDuration bool
+	for _, f := range families {
+		switch f.GetName() {
+		case "channelx_test_batch_size":
+			sawBatchSize = true
+			if got := f.GetMetric()[0].GetHistogram().GetSampleCount(); got != 1 {
+				t.Fatalf("expected batch_size sample count 1, got %d", got)
+			}
+		case "channelx_test_batch_duration_seconds":
+			sawBatchDuration = true
+			if got := f.GetMetric()[0].GetHistogram().GetSampleCount(); got != 1 {
+				t.Fatalf("expected batch_duration_seconds sample count 1, got %d", got)
+			}
+		}
+	}
+	if !sawBatchSize {
+		t.Fatal("expected a batch_size histogram to be registered")
+	}
+	if !sawBatchDuration {
+		t.Fatal("expected a batch_duration_seconds histogram to be registered")
+	}
+}
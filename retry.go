@@ -0,0 +1,103 @@
+package channelx
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// shouldRetry reports whether a failed batch is eligible for a retry at all,
+// deferring to RetryPredicate when one is configured.
+func (agt *AggregatorOf[T]) shouldRetry(err error) bool {
+	if agt.option.MaxRetries <= 0 {
+		return false
+	}
+
+	if agt.option.RetryPredicate != nil {
+		return agt.option.RetryPredicate(err)
+	}
+
+	return true
+}
+
+// retryLoop resubmits a failed batch to batchProcessor with exponential
+// backoff until it succeeds, MaxRetries is exhausted, RetryPredicate rejects
+// the error, or ctx is done, at which point the batch is dead-lettered.
+func (agt *AggregatorOf[T]) retryLoop(ctx context.Context, items []T, lastErr error) {
+	defer agt.wg.Done()
+
+	for attempt := 0; attempt < agt.option.MaxRetries; attempt++ {
+		backoff := agt.nextBackoff(attempt)
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			agt.deadLetter(items, ctx.Err())
+			return
+		}
+
+		if agt.option.Observer != nil {
+			agt.option.Observer.OnBatchStart(len(items))
+		}
+		start := time.Now()
+		lastErr = agt.runBatchProcessor(ctx, items)
+		if agt.option.Observer != nil {
+			agt.option.Observer.OnBatchEnd(len(items), time.Since(start), lastErr)
+		}
+
+		if lastErr == nil {
+			if agt.option.Logger != nil {
+				agt.option.Logger.Infoc("Aggregator", "%d items have been sent after %d retry/retries.", len(items), attempt+1)
+			}
+			return
+		}
+
+		if agt.option.Logger != nil {
+			agt.option.Logger.Errorc("Aggregator", lastErr, "retry %d/%d failed", attempt+1, agt.option.MaxRetries)
+		}
+
+		if agt.option.RetryPredicate != nil && !agt.option.RetryPredicate(lastErr) {
+			break
+		}
+	}
+
+	agt.deadLetter(items, lastErr)
+}
+
+// deadLetter hands an exhausted batch to DeadLetterHandler, falling back to
+// the regular failure path when none is configured.
+func (agt *AggregatorOf[T]) deadLetter(items []T, err error) {
+	if agt.option.DeadLetterHandler != nil {
+		agt.option.DeadLetterHandler(items, err)
+		return
+	}
+
+	agt.handleFailure(items, err)
+}
+
+// nextBackoff computes the delay before the given retry attempt (0-indexed),
+// doubling InitialBackoff each attempt, capping at MaxBackoff, and applying
+// BackoffJitter as a uniform factor in [1-jitter, 1+jitter].
+func (agt *AggregatorOf[T]) nextBackoff(attempt int) time.Duration {
+	backoff := agt.option.InitialBackoff
+	for i := 0; i < attempt; i++ {
+		backoff *= 2
+		if agt.option.MaxBackoff > 0 && backoff > agt.option.MaxBackoff {
+			backoff = agt.option.MaxBackoff
+			break
+		}
+	}
+
+	if agt.option.MaxBackoff > 0 && backoff > agt.option.MaxBackoff {
+		backoff = agt.option.MaxBackoff
+	}
+
+	if agt.option.BackoffJitter > 0 {
+		factor := 1 - agt.option.BackoffJitter + rand.Float64()*2*agt.option.BackoffJitter
+		backoff = time.Duration(float64(backoff) * factor)
+	}
+
+	return backoff
+}
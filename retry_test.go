@@ -0,0 +1,162 @@
+package channelx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRetrySucceedsWithinMaxRetries checks that a batch failing a few times
+// is resubmitted to batchProcessor, rather than going straight to
+// DeadLetterHandler, as long as it eventually succeeds within MaxRetries.
+func TestRetrySucceedsWithinMaxRetries(t *testing.T) {
+	var attempts int32
+	var deadLettered int32
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("transient failure")
+		}
+		return nil
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 1
+		o.ChannelBufferSize = 4
+		o.MaxRetries = 5
+		o.InitialBackoff = time.Millisecond
+		o.DeadLetterHandler = func(items []int, err error) {
+			atomic.AddInt32(&deadLettered, 1)
+		}
+		return o
+	})
+	agg.Start()
+	agg.Enqueue(1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&attempts) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	agg.Stop()
+
+	if got := atomic.LoadInt32(&attempts); got < 3 {
+		t.Fatalf("expected at least 3 attempts before success, got %d", got)
+	}
+	if got := atomic.LoadInt32(&deadLettered); got != 0 {
+		t.Fatalf("batch eventually succeeded, should not have been dead-lettered, got %d", got)
+	}
+}
+
+// TestRetryExhaustsToDeadLetter checks that a batch failing on every attempt
+// is handed to DeadLetterHandler once MaxRetries is exhausted, and not
+// retried indefinitely.
+func TestRetryExhaustsToDeadLetter(t *testing.T) {
+	var attempts int32
+	dead := make(chan []int, 1)
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("permanent failure")
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 1
+		o.ChannelBufferSize = 4
+		o.MaxRetries = 2
+		o.InitialBackoff = time.Millisecond
+		o.DeadLetterHandler = func(items []int, err error) {
+			select {
+			case dead <- items:
+			default:
+			}
+		}
+		return o
+	})
+	agg.Start()
+	agg.Enqueue(7)
+
+	select {
+	case items := <-dead:
+		if len(items) != 1 || items[0] != 7 {
+			t.Fatalf("expected dead-lettered batch [7], got %v", items)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the batch to be dead-lettered after MaxRetries attempts")
+	}
+	agg.Stop()
+
+	// Initial attempt plus MaxRetries retries.
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("expected 3 total attempts (1 initial + 2 retries), got %d", got)
+	}
+}
+
+// TestRetryPredicateStopsRetrying checks that RetryPredicate can veto a
+// retry, sending the batch straight to DeadLetterHandler even though
+// MaxRetries hasn't been exhausted.
+func TestRetryPredicateStopsRetrying(t *testing.T) {
+	var attempts int32
+	errPermanent := errors.New("do not retry me")
+	dead := make(chan error, 1)
+
+	agg := NewAggregatorOf[int](func(ctx context.Context, items []int) error {
+		atomic.AddInt32(&attempts, 1)
+		return errPermanent
+	}, func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+		o.Workers = 1
+		o.BatchSize = 1
+		o.ChannelBufferSize = 4
+		o.MaxRetries = 10
+		o.InitialBackoff = time.Millisecond
+		o.RetryPredicate = func(err error) bool { return !errors.Is(err, errPermanent) }
+		o.DeadLetterHandler = func(items []int, err error) {
+			select {
+			case dead <- err:
+			default:
+			}
+		}
+		return o
+	})
+	agg.Start()
+	agg.Enqueue(1)
+
+	select {
+	case err := <-dead:
+		if !errors.Is(err, errPermanent) {
+			t.Fatalf("expected dead-lettered error to be errPermanent, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected RetryPredicate to reject the error and dead-letter immediately")
+	}
+	agg.Stop()
+
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected exactly 1 attempt since RetryPredicate rejects retrying, got %d", got)
+	}
+}
+
+// TestNextBackoffDoublesAndCaps checks nextBackoff's doubling/cap behavior
+// directly, without jitter so the result is deterministic.
+func TestNextBackoffDoublesAndCaps(t *testing.T) {
+	agt := NewAggregatorOf[int](func(ctx context.Context, items []int) error { return nil },
+		func(o AggregatorOptionOf[int]) AggregatorOptionOf[int] {
+			o.InitialBackoff = 10 * time.Millisecond
+			o.MaxBackoff = 35 * time.Millisecond
+			return o
+		})
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 10 * time.Millisecond},
+		{1, 20 * time.Millisecond},
+		{2, 35 * time.Millisecond}, // would be 40ms uncapped
+		{3, 35 * time.Millisecond},
+	}
+	for _, c := range cases {
+		if got := agt.nextBackoff(c.attempt); got != c.want {
+			t.Fatalf("nextBackoff(%d) = %v, want %v", c.attempt, got, c.want)
+		}
+	}
+}